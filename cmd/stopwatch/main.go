@@ -0,0 +1,450 @@
+// Command stopwatch is a CLI frontend for the stopwatch library: it wires a
+// jsonstore.Store (or, when a daemon is running, an rpcstore.Store) into a
+// small set of flags for starting, stopping, listing and purging named
+// stopwatches.
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/xoltia/stopwatch"
+	"github.com/xoltia/stopwatch/internal/daemon"
+	"github.com/xoltia/stopwatch/internal/journal"
+	"github.com/xoltia/stopwatch/internal/jsonstore"
+	"github.com/xoltia/stopwatch/internal/rpcstore"
+	"github.com/xoltia/stopwatch/internal/tlog"
+)
+
+var log = tlog.New("cli")
+
+const Version = "0.1.3"
+
+type OutputType uint8
+
+const (
+	String OutputType = iota
+	Seconds
+	Milliseconds
+)
+
+// newStore prefers a daemon listening on socketPath, falling back to the
+// JSON file when none is running.
+func newStore(socketPath string) stopwatch.Store {
+	if rpcstore.Available(socketPath) {
+		log.Debug("using daemon at %s", socketPath)
+		return rpcstore.New(socketPath)
+	}
+
+	return jsonstore.New(jsonstore.DefaultPath())
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, uint32(time.Now().Unix()))
+	if _, err := rand.Read(b[4:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func parseSince(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+// parseAt parses the -at flag: an RFC3339 timestamp, a "@<unix-seconds>"
+// timestamp, or a negative duration (e.g. "-30m") measured back from now.
+func parseAt(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return now, nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "@"); ok {
+		sec, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid -at value %q: %s", s, err)
+		}
+		return time.Unix(sec, 0), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		if d >= 0 {
+			return time.Time{}, fmt.Errorf("invalid -at value %q: duration must be negative (e.g. -30m)", s)
+		}
+		return now.Add(d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid -at value %q: want RFC3339, @<unix>, or a duration", s)
+}
+
+// tagFlag collects repeated -tag key=val flags into a slice.
+type tagFlag []string
+
+func (t *tagFlag) String() string {
+	return strings.Join(*t, ",")
+}
+
+func (t *tagFlag) Set(v string) error {
+	*t = append(*t, v)
+	return nil
+}
+
+// entryJSON is the -json shape emitted by -stop and -ls.
+type entryJSON struct {
+	ID        string          `json:"id"`
+	StartTime time.Time       `json:"start_time"`
+	Tags      []string        `json:"tags,omitempty"`
+	Laps      []time.Duration `json:"laps,omitempty"`
+	Duration  time.Duration   `json:"duration,omitempty"`
+}
+
+// Print usage
+func Usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  stopwatch -start [-n <name>] [-tag key=val ...] [-at <RFC3339|@unix|-duration>]")
+	fmt.Fprintln(out, "  stopwatch -stop <id> [-s | -ms | -json]")
+	fmt.Fprintln(out, "  stopwatch -lap <id> [-s | -ms]")
+	fmt.Fprintln(out, "  stopwatch -ls [-s | -ms | -json]")
+	fmt.Fprintln(out, "  stopwatch -wait [-s | -ms] [-l]")
+	fmt.Fprintln(out, "  stopwatch -purge [-y]")
+	fmt.Fprintln(out, "  stopwatch -daemon [-socket <path>]")
+	fmt.Fprintln(out, "  stopwatch -history [-since <duration>] [-id <id>] [-json]")
+	fmt.Fprintln(out, "  stopwatch -stats [-id <id>] [-json]")
+	fmt.Fprintln(out, "  stopwatch -h")
+	fmt.Fprintln(out, "  stopwatch -v")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Options:")
+	flag.PrintDefaults()
+}
+
+// Start a new stopwatch and print id
+func Start(store stopwatch.Store, id string, tags []string, startTime time.Time) int {
+	if id == "" {
+		var err error
+		if id, err = newID(); err != nil {
+			fmt.Fprintf(os.Stderr, "error generating random id: %s\n", err)
+			return 1
+		}
+	}
+
+	if err := store.Start(id, startTime, tags...); err != nil {
+		fmt.Fprintf(os.Stderr, "error starting stopwatch: %s\n", err)
+		return 1
+	}
+
+	fmt.Println(id)
+	return 0
+}
+
+// Stop a stopwatch and print duration, or its full entry when asJSON
+func Stop(store stopwatch.Store, id string, outputType OutputType, asJSON bool) int {
+	entry, duration, err := store.Stop(id)
+	if errors.Is(err, stopwatch.ErrNotFound) {
+		fmt.Fprintf(os.Stderr, "no stopwatch with id %s found\n", id)
+		return 1
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error stopping stopwatch: %s\n", err)
+		return 1
+	}
+
+	if asJSON {
+		return printEntryJSON(entry, duration)
+	}
+
+	fmt.Println(DurationString(duration, outputType))
+	return 0
+}
+
+func printEntryJSON(entry stopwatch.Entry, duration time.Duration) int {
+	enc := json.NewEncoder(os.Stdout)
+	err := enc.Encode(entryJSON{
+		ID:        entry.ID,
+		StartTime: entry.StartTime,
+		Tags:      entry.Tags,
+		Laps:      entry.Laps,
+		Duration:  duration,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error encoding entry: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// Lap prints the elapsed duration for a stopwatch without stopping it
+func Lap(store stopwatch.Store, id string, outputType OutputType) int {
+	duration, err := store.Lap(id)
+	if errors.Is(err, stopwatch.ErrNotFound) {
+		fmt.Fprintf(os.Stderr, "no stopwatch with id %s found\n", id)
+		return 1
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error lapping stopwatch: %s\n", err)
+		return 1
+	}
+
+	fmt.Println(DurationString(duration, outputType))
+	return 0
+}
+
+// Daemon runs a long-lived stopwatch process that serves Start/Stop/Lap/List/
+// Purge over socketPath until it errors or is interrupted.
+func Daemon(socketPath string) int {
+	if err := daemon.Serve(socketPath); err != nil {
+		log.Fatal("daemon: %s", err)
+	}
+
+	return 0
+}
+
+// History streams journal events matching id (all ids if empty) and newer
+// than since (all time if zero).
+func History(id string, since time.Duration, asJSON bool) int {
+	events, err := journal.Read(journal.DefaultPath(), id, since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reading journal: %s\n", err)
+		return 1
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range events {
+			if err = enc.Encode(e); err != nil {
+				fmt.Fprintf(os.Stderr, "error encoding event: %s\n", err)
+				return 1
+			}
+		}
+		return 0
+	}
+
+	for _, e := range events {
+		fmt.Printf("%s\t%s\t%s", e.Time.Format(time.RFC3339), e.Event, e.ID)
+		if e.DurationMs != nil {
+			fmt.Printf("\t%dms", *e.DurationMs)
+		}
+		fmt.Println()
+	}
+
+	return 0
+}
+
+// Stats aggregates journal durations per id, optionally filtered to a
+// single id.
+func Stats(id string, asJSON bool) int {
+	events, err := journal.Read(journal.DefaultPath(), id, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reading journal: %s\n", err)
+		return 1
+	}
+
+	stats := journal.Aggregate(events)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, s := range stats {
+			if err = enc.Encode(s); err != nil {
+				fmt.Fprintf(os.Stderr, "error encoding stats: %s\n", err)
+				return 1
+			}
+		}
+		return 0
+	}
+
+	for _, s := range stats {
+		fmt.Printf("%s\tcount=%d\ttotal=%s\tmean=%s\tp50=%s\tp95=%s\tmax=%s\n",
+			s.ID, s.Count, s.Total, s.Mean, s.P50, s.P95, s.Max)
+	}
+
+	return 0
+}
+
+// List all running stopwatches, or their full entries when asJSON
+func List(store stopwatch.Store, outputType OutputType, asJSON bool) int {
+	entries, err := store.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error listing stopwatches: %s\n", err)
+		return 1
+	}
+
+	now := time.Now()
+
+	if asJSON {
+		for _, e := range entries {
+			if rc := printEntryJSON(e, now.Sub(e.StartTime)); rc != 0 {
+				return rc
+			}
+		}
+		return 0
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s\t", e.ID)
+		fmt.Printf("%s\t", DurationString(now.Sub(e.StartTime), outputType))
+		fmt.Println(e.StartTime.Format(time.RFC3339))
+	}
+
+	return 0
+}
+
+// Wait for SIGINT and print duration
+func Wait(live bool, outputType OutputType) int {
+	start := time.Now()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+
+	if !live {
+		<-signalChan
+		fmt.Print("\033[2K\r")
+		fmt.Println(DurationString(time.Since(start), outputType))
+		return 0
+	}
+
+	ticker := time.NewTicker(time.Millisecond * 100)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-signalChan:
+			fmt.Print("\033[2K\r")
+			fmt.Println(DurationString(time.Since(start), outputType))
+			return 0
+		case <-ticker.C:
+			fmt.Print("\033[2K\r")
+			fmt.Printf("%s", DurationString(time.Since(start).Round(time.Millisecond*100), outputType))
+		}
+	}
+}
+
+func Purge(store stopwatch.Store, skipConfirmation bool) int {
+	if !skipConfirmation {
+		fmt.Fprintf(os.Stderr, "Are you sure you want to remove the stopwatch file? [y/N] ")
+		var answer string
+
+		if _, err := fmt.Scanln(&answer); err != nil {
+			fmt.Fprintf(os.Stderr, "error reading input: %s\n", err)
+			return 1
+		}
+
+		if answer != "y" && answer != "Y" {
+			return 0
+		}
+	}
+
+	if err := store.Purge(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "error removing stopwatch file: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// Format duration according to output type
+func DurationString(d time.Duration, format OutputType) string {
+	if format == Seconds {
+		return fmt.Sprintf("%f", d.Seconds())
+	} else if format == Milliseconds {
+		return fmt.Sprintf("%d", d.Milliseconds())
+	} else {
+		return fmt.Sprintf("%s", d)
+	}
+}
+
+var (
+	startFlag        = flag.Bool("start", false, "start a new stopwatch")
+	idFlag           = flag.String("n", "", "id of the stopwatch")
+	atFlag           = flag.String("at", "", "register the stopwatch as having started earlier: RFC3339, @<unix>, or a duration like -30m (only with -start)")
+	stopFlag         = flag.String("stop", "", "stop a stopwatch")
+	lapFlag          = flag.String("lap", "", "print elapsed duration for a stopwatch without stopping it")
+	listFlag         = flag.Bool("ls", false, "list all running stopwatches")
+	versionFlag      = flag.Bool("v", false, "print version")
+	secondsFlag      = flag.Bool("s", false, "output duration in seconds")
+	millisecondsFlag = flag.Bool("ms", false, "output duration in milliseconds")
+	waitingFlag      = flag.Bool("wait", false, "start a new stopwatch and wait for SIGINT (does not write to file)")
+	liveFlag         = flag.Bool("l", false, "live output (only with -wait)")
+	purgeFlag        = flag.Bool("purge", false, "remove stopwatch file")
+	confirmFlag      = flag.Bool("y", false, "skip confirmation (only with -purge)")
+	daemonFlag       = flag.Bool("daemon", false, "run a daemon serving stopwatch requests over a Unix socket")
+	socketFlag       = flag.String("socket", daemon.DefaultSocketPath(), "daemon socket path (only with -daemon, or to reach a daemon)")
+	historyFlag      = flag.Bool("history", false, "stream journal events (only with -id, -since, -json)")
+	statsFlag        = flag.Bool("stats", false, "aggregate journal durations per id (only with -id, -json)")
+	sinceFlag        = flag.String("since", "", "only show history events newer than this duration (only with -history)")
+	idFilterFlag     = flag.String("id", "", "only show history/stats events for this id")
+	jsonFlag         = flag.Bool("json", false, "emit -history/-stats/-ls/-stop output as JSON")
+	tagsFlag         tagFlag
+)
+
+func init() {
+	flag.Var(&tagsFlag, "tag", "key=val tag to attach at start time (only with -start, repeatable)")
+}
+
+func main() {
+	flag.Parse()
+	flag.Usage = Usage
+
+	var outputType OutputType
+
+	if *secondsFlag {
+		outputType = Seconds
+	} else if *millisecondsFlag {
+		outputType = Milliseconds
+	} else {
+		outputType = String
+	}
+
+	if *versionFlag {
+		fmt.Println(Version)
+		os.Exit(0)
+	} else if *daemonFlag {
+		os.Exit(Daemon(*socketFlag))
+	} else if *startFlag {
+		startTime, err := parseAt(*atFlag, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(Start(newStore(*socketFlag), *idFlag, tagsFlag, startTime))
+	} else if *stopFlag != "" {
+		os.Exit(Stop(newStore(*socketFlag), *stopFlag, outputType, *jsonFlag))
+	} else if *lapFlag != "" {
+		os.Exit(Lap(newStore(*socketFlag), *lapFlag, outputType))
+	} else if *listFlag {
+		os.Exit(List(newStore(*socketFlag), outputType, *jsonFlag))
+	} else if *waitingFlag {
+		os.Exit(Wait(*liveFlag, outputType))
+	} else if *purgeFlag {
+		os.Exit(Purge(newStore(*socketFlag), *confirmFlag))
+	} else if *historyFlag {
+		since, err := parseSince(*sinceFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error parsing -since: %s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(History(*idFilterFlag, since, *jsonFlag))
+	} else if *statsFlag {
+		os.Exit(Stats(*idFilterFlag, *jsonFlag))
+	}
+
+	flag.Usage()
+	os.Exit(1)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAt(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name    string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"empty defaults to now", "", now, false},
+		{"rfc3339", "2024-03-15T10:00:00Z", time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC), false},
+		{"unix timestamp", "@1710500000", time.Unix(1710500000, 0), false},
+		{"negative duration", "-30m", now.Add(-30 * time.Minute), false},
+		{"positive duration rejected", "30m", time.Time{}, true},
+		{"zero duration rejected", "0s", time.Time{}, true},
+		{"invalid unix timestamp", "@notanumber", time.Time{}, true},
+		{"garbage", "not-a-time", time.Time{}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parseAt(c.in, now)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("parseAt(%q) = %v, want error", c.in, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseAt(%q) returned error: %s", c.in, err)
+			}
+
+			if !got.Equal(c.want) {
+				t.Fatalf("parseAt(%q) = %v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
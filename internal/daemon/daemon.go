@@ -0,0 +1,131 @@
+// Package daemon implements a long-lived stopwatch process that serves a
+// jsonstore.Store over a Unix domain socket, so that a client can avoid
+// paying process-startup cost on every call. The daemon backs onto the
+// same on-disk file as the CLI's jsonstore fallback, so a stopwatch
+// started, stopped, or purged through the socket is immediately visible
+// to (and durable against) a plain file-backed invocation, whether or not
+// a daemon happens to be running at the time.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/xoltia/stopwatch"
+	"github.com/xoltia/stopwatch/internal/jsonstore"
+	"github.com/xoltia/stopwatch/internal/tlog"
+)
+
+var log = tlog.New("daemon")
+
+// Request is a single operation sent to the daemon, JSON-framed over the
+// socket connection.
+type Request struct {
+	Op   string    `json:"op"`
+	ID   string    `json:"id,omitempty"`
+	Time time.Time `json:"time,omitempty"`
+	Tags []string  `json:"tags,omitempty"`
+}
+
+// Response is the daemon's reply to a Request.
+type Response struct {
+	Error    string            `json:"error,omitempty"`
+	Duration time.Duration     `json:"duration,omitempty"`
+	Entry    stopwatch.Entry   `json:"entry,omitempty"`
+	Entries  []stopwatch.Entry `json:"entries,omitempty"`
+}
+
+// DefaultSocketPath returns the default socket path, honoring
+// XDG_RUNTIME_DIR.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir + "/stopwatch.sock"
+	}
+
+	return "/tmp/stopwatch.sock"
+}
+
+// Serve runs the daemon, accepting connections on socketPath until the
+// listener errors or is closed. It removes a stale socket file left behind
+// by a previous, uncleanly terminated daemon before listening.
+func Serve(socketPath string) error {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing stale socket: %s", err)
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %s", socketPath, err)
+	}
+	defer ln.Close()
+
+	log.Info("listening on %s", socketPath)
+
+	store := jsonstore.New(jsonstore.DefaultPath())
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return fmt.Errorf("error accepting connection: %s", err)
+		}
+
+		go serveConn(conn, store)
+	}
+}
+
+func serveConn(conn net.Conn, store stopwatch.Store) {
+	defer conn.Close()
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+
+		if err := enc.Encode(handle(store, req)); err != nil {
+			log.Warn("encoding response: %s", err)
+			return
+		}
+	}
+}
+
+func handle(store stopwatch.Store, req Request) Response {
+	switch req.Op {
+	case "start":
+		if err := store.Start(req.ID, req.Time, req.Tags...); err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{}
+	case "stop":
+		entry, d, err := store.Stop(req.ID)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{Duration: d, Entry: entry}
+	case "lap":
+		d, err := store.Lap(req.ID)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{Duration: d}
+	case "list":
+		entries, err := store.List()
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{Entries: entries}
+	case "purge":
+		if err := store.Purge(); err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{}
+	default:
+		return Response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+	}
+}
@@ -0,0 +1,150 @@
+// Package journal implements an append-only, line-delimited JSON log of
+// stopwatch mutations (similar to syncthing's auditservice), queryable via
+// the CLI's -history and -stats flags.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+)
+
+// Event kinds recorded in the journal.
+const (
+	EventStart = "start"
+	EventStop  = "stop"
+	EventPurge = "purge"
+)
+
+// maxSize is the size, in bytes, at which Append rotates the journal to a
+// single ".1" backup before continuing to write.
+const maxSize = 10 * 1024 * 1024 // 10 MiB
+
+// Event is a single journaled stopwatch mutation.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	ID         string    `json:"id,omitempty"`
+	DurationMs *int64    `json:"duration_ms,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+}
+
+// DefaultPath returns the default events.jsonl location, honoring
+// XDG_DATA_HOME and falling back to ~/.local/share/stopwatch.
+func DefaultPath() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return path.Join(dir, "stopwatch", "events.jsonl")
+	}
+
+	return path.Join(os.Getenv("HOME"), ".local", "share", "stopwatch", "events.jsonl")
+}
+
+// Append records event to the journal at path, rotating the existing
+// journal to a ".1" backup first if it has grown past maxSize. Callers that
+// need the journal to stay consistent with other on-disk state (e.g.
+// jsonstore) must call Append while still holding that state's lock.
+func Append(journalPath string, event Event) error {
+	if err := os.MkdirAll(path.Dir(journalPath), os.ModePerm); err != nil {
+		return fmt.Errorf("error creating directory: %s: %s", path.Dir(journalPath), err)
+	}
+
+	if err := rotate(journalPath); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(journalPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("error opening journal: %s: %s", journalPath, err)
+	}
+	defer f.Close()
+
+	if err = json.NewEncoder(f).Encode(event); err != nil {
+		return fmt.Errorf("error encoding event: %s", err)
+	}
+
+	return nil
+}
+
+func rotate(journalPath string) error {
+	stat, err := os.Stat(journalPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error stating journal: %s: %s", journalPath, err)
+	}
+
+	if stat.Size() < maxSize {
+		return nil
+	}
+
+	if err = os.Rename(journalPath, journalPath+".1"); err != nil {
+		return fmt.Errorf("error rotating journal: %s: %s", journalPath, err)
+	}
+
+	return nil
+}
+
+// Read streams every event in the journal at path, oldest first, filtered
+// by id (ignored when empty) and by since (events older than since before
+// now are skipped; ignored when zero). It includes the path+".1" backup
+// left behind by a prior rotate, if one exists, ahead of path itself.
+func Read(path string, id string, since time.Duration) ([]Event, error) {
+	var cutoff time.Time
+	if since > 0 {
+		cutoff = time.Now().Add(-since)
+	}
+
+	backup, err := readFile(path + ".1")
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, e := range append(backup, current...) {
+		if id != "" && e.ID != id {
+			continue
+		}
+
+		if !cutoff.IsZero() && e.Time.Before(cutoff) {
+			continue
+		}
+
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// readFile decodes every event in the journal file at path, oldest first.
+// A missing file is not an error: it yields no events.
+func readFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error opening journal: %s: %s", path, err)
+	}
+	defer f.Close()
+
+	var events []Event
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var e Event
+		if err = dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("error decoding event: %s", err)
+		}
+
+		events = append(events, e)
+	}
+
+	return events, nil
+}
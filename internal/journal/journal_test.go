@@ -0,0 +1,124 @@
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeEvents(t *testing.T, path string, events ...Event) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("creating %s: %s", path, err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			t.Fatalf("encoding event: %s", err)
+		}
+	}
+}
+
+func TestRotateRenamesPastMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	if err := os.WriteFile(path, make([]byte, maxSize), 0o644); err != nil {
+		t.Fatalf("writing oversized journal: %s", err)
+	}
+
+	if err := rotate(path); err != nil {
+		t.Fatalf("rotate: %s", err)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected backup at %s.1: %s", path, err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be renamed away, stat err = %v", path, err)
+	}
+}
+
+func TestRotateLeavesSmallJournalAlone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("writing journal: %s", err)
+	}
+
+	if err := rotate(path); err != nil {
+		t.Fatalf("rotate: %s", err)
+	}
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("did not expect a backup file")
+	}
+}
+
+func TestReadMergesBackupAheadOfCurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	older := Event{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Event: EventStart, ID: "pre-rotation"}
+	newer := Event{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Event: EventStart, ID: "post-rotation"}
+
+	writeEvents(t, path+".1", older)
+	writeEvents(t, path, newer)
+
+	events, err := Read(path, "", 0)
+	if err != nil {
+		t.Fatalf("Read: %s", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Read() = %d events, want 2", len(events))
+	}
+
+	if events[0].ID != "pre-rotation" || events[1].ID != "post-rotation" {
+		t.Fatalf("Read() = %v, want pre-rotation before post-rotation", events)
+	}
+}
+
+func TestReadFiltersByIDAndSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	now := time.Now()
+	writeEvents(t, path,
+		Event{Time: now.Add(-time.Hour), Event: EventStart, ID: "old"},
+		Event{Time: now, Event: EventStart, ID: "new"},
+		Event{Time: now, Event: EventStart, ID: "other"},
+	)
+
+	events, err := Read(path, "new", 0)
+	if err != nil {
+		t.Fatalf("Read: %s", err)
+	}
+	if len(events) != 1 || events[0].ID != "new" {
+		t.Fatalf("Read(id=new) = %v, want only the new event", events)
+	}
+
+	events, err = Read(path, "", time.Minute)
+	if err != nil {
+		t.Fatalf("Read: %s", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Read(since=1m) = %d events, want 2 (old excluded)", len(events))
+	}
+}
+
+func TestReadMissingJournalReturnsNoEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	events, err := Read(path, "", 0)
+	if err != nil {
+		t.Fatalf("Read: %s", err)
+	}
+	if events != nil {
+		t.Fatalf("Read() = %v, want nil", events)
+	}
+}
@@ -0,0 +1,74 @@
+package journal
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats aggregates the stop-event durations recorded for a single id.
+type Stats struct {
+	ID    string
+	Count int
+	Total time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+// Aggregate computes per-id Stats from a set of events, ignoring any event
+// that isn't a stop event with a recorded duration. Results are sorted by
+// id.
+func Aggregate(events []Event) []Stats {
+	byID := make(map[string][]time.Duration)
+	for _, e := range events {
+		if e.Event != EventStop || e.DurationMs == nil {
+			continue
+		}
+		byID[e.ID] = append(byID[e.ID], time.Duration(*e.DurationMs)*time.Millisecond)
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	stats := make([]Stats, 0, len(ids))
+	for _, id := range ids {
+		durations := byID[id]
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+
+		stats = append(stats, Stats{
+			ID:    id,
+			Count: len(durations),
+			Total: total,
+			Mean:  total / time.Duration(len(durations)),
+			P50:   percentile(durations, 0.50),
+			P95:   percentile(durations, 0.95),
+			Max:   durations[len(durations)-1],
+		})
+	}
+
+	return stats
+}
+
+// percentile returns the p-th percentile (0..1) of a sorted duration slice
+// using nearest-rank.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+
+	return sorted[idx]
+}
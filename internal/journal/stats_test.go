@@ -0,0 +1,63 @@
+package journal
+
+import (
+	"testing"
+	"time"
+)
+
+func durationMs(ms int64) *int64 {
+	return &ms
+}
+
+func TestAggregate(t *testing.T) {
+	events := []Event{
+		{Event: EventStop, ID: "a", DurationMs: durationMs(100)},
+		{Event: EventStop, ID: "a", DurationMs: durationMs(200)},
+		{Event: EventStop, ID: "a", DurationMs: durationMs(300)},
+		{Event: EventStop, ID: "a", DurationMs: durationMs(400)},
+		{Event: EventStop, ID: "a", DurationMs: durationMs(500)},
+		{Event: EventStop, ID: "b", DurationMs: durationMs(1000)},
+		{Event: EventStart, ID: "a"},                 // not a stop event: ignored
+		{Event: EventStop, ID: "a", DurationMs: nil}, // no recorded duration: ignored
+	}
+
+	stats := Aggregate(events)
+	if len(stats) != 2 {
+		t.Fatalf("Aggregate() = %d stats, want 2", len(stats))
+	}
+
+	// Sorted by id.
+	a, b := stats[0], stats[1]
+	if a.ID != "a" || b.ID != "b" {
+		t.Fatalf("Aggregate() ids = %q, %q, want a, b", a.ID, b.ID)
+	}
+
+	if a.Count != 5 {
+		t.Fatalf("a.Count = %d, want 5", a.Count)
+	}
+	if a.Total != 1500*time.Millisecond {
+		t.Fatalf("a.Total = %s, want 1.5s", a.Total)
+	}
+	if a.Mean != 300*time.Millisecond {
+		t.Fatalf("a.Mean = %s, want 300ms", a.Mean)
+	}
+	if a.P50 != 300*time.Millisecond {
+		t.Fatalf("a.P50 = %s, want 300ms", a.P50)
+	}
+	if a.P95 != 500*time.Millisecond {
+		t.Fatalf("a.P95 = %s, want 500ms", a.P95)
+	}
+	if a.Max != 500*time.Millisecond {
+		t.Fatalf("a.Max = %s, want 500ms", a.Max)
+	}
+
+	if b.Count != 1 || b.Max != time.Second {
+		t.Fatalf("b = %+v, want a single 1s entry", b)
+	}
+}
+
+func TestPercentileEmpty(t *testing.T) {
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Fatalf("percentile(nil) = %s, want 0", got)
+	}
+}
@@ -0,0 +1,326 @@
+// Package jsonstore implements stopwatch.Store on top of a single JSON file
+// guarded by an flock, which is how the original stopwatch CLI persisted
+// state before Store was extracted as an interface.
+package jsonstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/xoltia/stopwatch"
+	"github.com/xoltia/stopwatch/internal/journal"
+	"github.com/xoltia/stopwatch/internal/lockedfile"
+	"github.com/xoltia/stopwatch/internal/tlog"
+)
+
+var log = tlog.New("jsonstore")
+
+// errDecode marks a read failure as a corrupt-JSON decode error, as opposed
+// to e.g. an I/O error, so List knows it's safe to fall back to the file's
+// mtime rather than surfacing the error.
+var errDecode = errors.New("jsonstore: error decoding entries")
+
+// Store is a stopwatch.Store backed by a JSON file on disk.
+type Store struct {
+	path string
+}
+
+// New returns a Store that persists entries to the JSON file at path.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the default stopwatch.json location, honoring
+// XDG_DATA_HOME and falling back to ~/.local/share/stopwatch.
+func DefaultPath() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return path.Join(dir, "stopwatch", "stopwatch.json")
+	}
+
+	return path.Join(os.Getenv("HOME"), ".local", "share", "stopwatch", "stopwatch.json")
+}
+
+// diskEntry is the on-disk representation of a stopwatch.Entry.
+type diskEntry struct {
+	StartTime time.Time       `json:"start_time"`
+	Tags      []string        `json:"tags,omitempty"`
+	Laps      []time.Duration `json:"laps,omitempty"`
+}
+
+// UnmarshalJSON accepts both the current object shape and the legacy shape
+// written by versions before laps and tags existed, where a value was a
+// bare time.Time rather than an object.
+func (e *diskEntry) UnmarshalJSON(data []byte) error {
+	var t time.Time
+	if err := json.Unmarshal(data, &t); err == nil {
+		e.StartTime = t
+		return nil
+	}
+
+	type alias diskEntry
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+
+	*e = diskEntry(a)
+	return nil
+}
+
+func (e diskEntry) toStopwatchEntry(id string) stopwatch.Entry {
+	return stopwatch.Entry{ID: id, StartTime: e.StartTime, Tags: e.Tags, Laps: e.Laps}
+}
+
+// entries is the on-disk representation: id -> entry.
+type entries map[string]diskEntry
+
+func (s *Store) open() (file *lockedfile.File, err error) {
+	if err = os.MkdirAll(path.Dir(s.path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("error creating directory: %s: %s", path.Dir(s.path), err)
+	}
+
+	file, err = lockedfile.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("opened and locked %s", s.path)
+	return file, nil
+}
+
+// journalPath returns the events.jsonl location alongside the JSON file.
+// appendEvent must only be called while file is still open, so the event
+// is ordered consistently with other processes' mutations of s.path.
+func (s *Store) journalPath() string {
+	return path.Join(path.Dir(s.path), "events.jsonl")
+}
+
+func (s *Store) appendEvent(e journal.Event) {
+	if err := journal.Append(s.journalPath(), e); err != nil {
+		log.Warn("appending journal event: %s", err)
+	}
+}
+
+func (s *Store) close(file *lockedfile.File) error {
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	log.Debug("closed %s", file.Name())
+	return nil
+}
+
+func (s *Store) read(file *lockedfile.File) (entries, error) {
+	e := make(entries)
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error getting file stats: %s", err)
+	}
+
+	if stat.Size() > 0 {
+		if err = json.NewDecoder(file).Decode(&e); err != nil {
+			return nil, fmt.Errorf("%w: %s", errDecode, err)
+		}
+	}
+
+	return e, nil
+}
+
+func (s *Store) write(file *lockedfile.File, e entries) error {
+	if err := file.Truncate(0); err != nil {
+		return fmt.Errorf("error truncating file: %s", err)
+	}
+
+	if _, err := file.Seek(0, 0); err != nil {
+		return fmt.Errorf("error seeking file: %s", err)
+	}
+
+	if err := json.NewEncoder(file).Encode(e); err != nil {
+		return fmt.Errorf("error encoding entries: %s", err)
+	}
+
+	// Set the file's mtime to the oldest running entry's start time, so that
+	// if the JSON is later corrupted, List can still recover a conservative
+	// floor for how long something has been running from the mtime alone.
+	now := time.Now()
+	earliest := now
+	for _, entry := range e {
+		if entry.StartTime.Before(earliest) {
+			earliest = entry.StartTime
+		}
+	}
+
+	if err := os.Chtimes(s.path, now, earliest); err != nil {
+		log.Warn("setting mtime: %s", err)
+	}
+
+	return nil
+}
+
+// Start implements stopwatch.Store.
+func (s *Store) Start(id string, t time.Time, tags ...string) error {
+	file, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := s.close(file); cerr != nil {
+			log.Warn("closing %s: %s", file.Name(), cerr)
+		}
+	}()
+
+	e, err := s.read(file)
+	if err != nil {
+		return err
+	}
+
+	e[id] = diskEntry{StartTime: t, Tags: tags}
+
+	if err = s.write(file, e); err != nil {
+		return err
+	}
+
+	s.appendEvent(journal.Event{Time: t, Event: journal.EventStart, ID: id, Tags: tags})
+	log.Debug("started %s", id)
+	return nil
+}
+
+// Stop implements stopwatch.Store.
+func (s *Store) Stop(id string) (stopwatch.Entry, time.Duration, error) {
+	file, err := s.open()
+	if err != nil {
+		return stopwatch.Entry{}, 0, err
+	}
+	defer func() {
+		if cerr := s.close(file); cerr != nil {
+			log.Warn("closing %s: %s", file.Name(), cerr)
+		}
+	}()
+
+	e, err := s.read(file)
+	if err != nil {
+		return stopwatch.Entry{}, 0, err
+	}
+
+	entry, ok := e[id]
+	if !ok {
+		return stopwatch.Entry{}, 0, stopwatch.ErrNotFound
+	}
+
+	delete(e, id)
+
+	if err = s.write(file, e); err != nil {
+		return stopwatch.Entry{}, 0, err
+	}
+
+	duration := time.Since(entry.StartTime)
+	durationMs := duration.Milliseconds()
+	s.appendEvent(journal.Event{Time: time.Now(), Event: journal.EventStop, ID: id, DurationMs: &durationMs, Tags: entry.Tags})
+	log.Debug("stopped %s", id)
+	return entry.toStopwatchEntry(id), duration, nil
+}
+
+// Lap implements stopwatch.Store.
+func (s *Store) Lap(id string) (time.Duration, error) {
+	file, err := s.open()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := s.close(file); cerr != nil {
+			log.Warn("closing %s: %s", file.Name(), cerr)
+		}
+	}()
+
+	e, err := s.read(file)
+	if err != nil {
+		return 0, err
+	}
+
+	entry, ok := e[id]
+	if !ok {
+		return 0, stopwatch.ErrNotFound
+	}
+
+	var elapsedLaps time.Duration
+	for _, l := range entry.Laps {
+		elapsedLaps += l
+	}
+
+	split := time.Since(entry.StartTime) - elapsedLaps
+	entry.Laps = append(entry.Laps, split)
+	e[id] = entry
+
+	if err = s.write(file, e); err != nil {
+		return 0, err
+	}
+
+	log.Debug("lapped %s", id)
+	return split, nil
+}
+
+// List implements stopwatch.Store.
+func (s *Store) List() ([]stopwatch.Entry, error) {
+	file, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := s.close(file); cerr != nil {
+			log.Warn("closing %s: %s", file.Name(), cerr)
+		}
+	}()
+
+	e, err := s.read(file)
+	if errors.Is(err, errDecode) {
+		log.Warn("recovering from mtime: %s", err)
+		return s.recoverFromMtime(file)
+	} else if err != nil {
+		return nil, err
+	}
+
+	result := make([]stopwatch.Entry, 0, len(e))
+	for id, entry := range e {
+		result = append(result, entry.toStopwatchEntry(id))
+	}
+
+	return result, nil
+}
+
+// recoverFromMtime returns a single synthetic entry whose StartTime is the
+// file's mtime -- the earliest start time as of the last successful write
+// (see write) -- so -ls can still report a conservative lower bound on
+// elapsed time when the JSON itself can't be decoded.
+func (s *Store) recoverFromMtime(file *lockedfile.File) ([]stopwatch.Entry, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error getting file stats: %s", err)
+	}
+
+	return []stopwatch.Entry{{ID: "(recovered)", StartTime: stat.ModTime()}}, nil
+}
+
+// Purge implements stopwatch.Store.
+func (s *Store) Purge() error {
+	file, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := s.close(file); cerr != nil {
+			log.Warn("closing %s: %s", file.Name(), cerr)
+		}
+	}()
+
+	if err = os.Remove(s.path); err != nil {
+		return err
+	}
+
+	s.appendEvent(journal.Event{Time: time.Now(), Event: journal.EventPurge})
+	log.Debug("purged %s", s.path)
+	return nil
+}
@@ -0,0 +1,58 @@
+package jsonstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestListRecoversFromMtimeOnDecodeError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stopwatch.json")
+
+	if err := os.WriteFile(path, []byte("not valid json"), 0o644); err != nil {
+		t.Fatalf("writing corrupt file: %s", err)
+	}
+
+	mtime := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("setting mtime: %s", err)
+	}
+
+	entries, err := New(path).List()
+	if err != nil {
+		t.Fatalf("List() returned error: %s", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("List() = %d entries, want 1", len(entries))
+	}
+
+	if !entries[0].StartTime.Equal(mtime) {
+		t.Fatalf("recovered StartTime = %v, want %v", entries[0].StartTime, mtime)
+	}
+}
+
+func TestStartWritesEarliestStartTimeAsMtime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stopwatch.json")
+	s := New(path)
+
+	earlier := time.Now().Add(-time.Hour)
+	later := time.Now()
+
+	if err := s.Start("older", earlier); err != nil {
+		t.Fatalf("Start(older): %s", err)
+	}
+	if err := s.Start("newer", later); err != nil {
+		t.Fatalf("Start(newer): %s", err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %s", err)
+	}
+
+	if !stat.ModTime().Equal(earlier) {
+		t.Fatalf("mtime = %v, want earliest start time %v", stat.ModTime(), earlier)
+	}
+}
@@ -0,0 +1,14 @@
+// Package lockedfile opens a single file with an exclusive, whole-process
+// lock so that jsonstore never races another stopwatch invocation writing
+// the same file. The locking primitive differs per OS, so the actual work
+// happens in the lockedfile_*.go files selected by build constraint; this
+// file only declares the shared File type and doc comment callers see.
+package lockedfile
+
+import "os"
+
+// File is a file opened with an exclusive lock held for its lifetime.
+// Close releases the lock in addition to closing the underlying os.File.
+type File struct {
+	*os.File
+}
@@ -0,0 +1,52 @@
+//go:build !unix && !windows
+
+package lockedfile
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// On platforms with no native advisory locking primitive (e.g. Plan 9), we
+// fall back to an atomically-created sidecar file as a lock: whoever
+// manages to create it with O_EXCL holds the lock, and removing it releases
+// it. This is cooperative rather than kernel-enforced, but it's enough to
+// keep two stopwatch invocations from interleaving writes.
+const lockPollInterval = 10 * time.Millisecond
+
+// Open opens (creating if necessary) the file at path and blocks until the
+// path+".lock" sidecar can be created exclusively.
+func Open(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %s: %s", path, err)
+	}
+
+	lockPath := path + ".lock"
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_RDWR, os.ModePerm)
+		if err == nil {
+			lock.Close()
+			break
+		}
+
+		if !os.IsExist(err) {
+			f.Close()
+			return nil, fmt.Errorf("error locking file: %s", err)
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+
+	return &File{f}, nil
+}
+
+// Close unlocks and closes the file.
+func (f *File) Close() error {
+	if err := os.Remove(f.Name() + ".lock"); err != nil {
+		return fmt.Errorf("error unlocking file: %s", err)
+	}
+
+	return f.File.Close()
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package lockedfile
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Open opens (creating if necessary) the file at path and blocks until an
+// exclusive flock is acquired on it.
+func Open(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %s: %s", path, err)
+	}
+
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error locking file: %s", err)
+	}
+
+	return &File{f}, nil
+}
+
+// Close unlocks and closes the file.
+func (f *File) Close() error {
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_UN); err != nil {
+		return fmt.Errorf("error unlocking file: %s", err)
+	}
+
+	return f.File.Close()
+}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package lockedfile
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Open opens (creating if necessary) the file at path and blocks until an
+// exclusive lock is acquired on it via LockFileEx.
+func Open(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %s: %s", path, err)
+	}
+
+	ol := new(windows.Overlapped)
+	err = windows.LockFileEx(
+		windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK,
+		0,
+		1, 0,
+		ol,
+	)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error locking file: %s", err)
+	}
+
+	return &File{f}, nil
+}
+
+// Close unlocks and closes the file.
+func (f *File) Close() error {
+	ol := new(windows.Overlapped)
+	if err := windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol); err != nil {
+		return fmt.Errorf("error unlocking file: %s", err)
+	}
+
+	return f.File.Close()
+}
@@ -0,0 +1,93 @@
+// Package rpcstore implements stopwatch.Store by delegating to a daemon
+// over a Unix domain socket, so the CLI can transparently prefer talking
+// to an already-running daemon over paying jsonstore's open/flock cycle
+// itself.
+package rpcstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/xoltia/stopwatch"
+	"github.com/xoltia/stopwatch/internal/daemon"
+)
+
+// Store is a stopwatch.Store that talks to a daemon listening on a Unix
+// domain socket.
+type Store struct {
+	socketPath string
+}
+
+// New returns a Store that dials socketPath for every call.
+func New(socketPath string) *Store {
+	return &Store{socketPath: socketPath}
+}
+
+// Available reports whether a daemon is listening on socketPath.
+func Available(socketPath string) bool {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+	return true
+}
+
+func (s *Store) call(req daemon.Request) (daemon.Response, error) {
+	conn, err := net.Dial("unix", s.socketPath)
+	if err != nil {
+		return daemon.Response{}, fmt.Errorf("error dialing daemon: %s", err)
+	}
+	defer conn.Close()
+
+	if err = json.NewEncoder(conn).Encode(req); err != nil {
+		return daemon.Response{}, fmt.Errorf("error sending request: %s", err)
+	}
+
+	var resp daemon.Response
+	if err = json.NewDecoder(conn).Decode(&resp); err != nil {
+		return daemon.Response{}, fmt.Errorf("error reading response: %s", err)
+	}
+
+	if resp.Error != "" {
+		if resp.Error == stopwatch.ErrNotFound.Error() {
+			return daemon.Response{}, stopwatch.ErrNotFound
+		}
+		return daemon.Response{}, fmt.Errorf("%s", resp.Error)
+	}
+
+	return resp, nil
+}
+
+// Start implements stopwatch.Store.
+func (s *Store) Start(id string, t time.Time, tags ...string) error {
+	_, err := s.call(daemon.Request{Op: "start", ID: id, Time: t, Tags: tags})
+	return err
+}
+
+// Stop implements stopwatch.Store.
+func (s *Store) Stop(id string) (stopwatch.Entry, time.Duration, error) {
+	resp, err := s.call(daemon.Request{Op: "stop", ID: id})
+	return resp.Entry, resp.Duration, err
+}
+
+// Lap implements stopwatch.Store.
+func (s *Store) Lap(id string) (time.Duration, error) {
+	resp, err := s.call(daemon.Request{Op: "lap", ID: id})
+	return resp.Duration, err
+}
+
+// List implements stopwatch.Store.
+func (s *Store) List() ([]stopwatch.Entry, error) {
+	resp, err := s.call(daemon.Request{Op: "list"})
+	return resp.Entries, err
+}
+
+// Purge implements stopwatch.Store.
+func (s *Store) Purge() error {
+	_, err := s.call(daemon.Request{Op: "purge"})
+	return err
+}
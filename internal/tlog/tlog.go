@@ -0,0 +1,69 @@
+// Package tlog provides a small leveled, facet-tagged logger in the style of
+// syncthing's tlog package. Debug output is off by default and is enabled
+// per-facet via the STOPWATCH_TRACE environment variable (a comma-separated
+// list of facet names, or "all"/"*" to enable everything), so the CLI and
+// any future daemon or server code can share consistent, quiet-by-default
+// diagnostics instead of scattered fmt.Fprintf(os.Stderr, ...) calls.
+package tlog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var traced = parseTrace(os.Getenv("STOPWATCH_TRACE"))
+
+func parseTrace(v string) map[string]bool {
+	facets := make(map[string]bool)
+	for _, f := range strings.Split(v, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			facets[f] = true
+		}
+	}
+	return facets
+}
+
+// Logger emits leveled diagnostics to stderr, tagged with a facet name.
+type Logger struct {
+	facet string
+}
+
+// New returns a Logger for the given facet, e.g. "jsonstore" or "cli".
+func New(facet string) *Logger {
+	return &Logger{facet: facet}
+}
+
+func (l *Logger) output(level, format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %s [%s] %s\n",
+		time.Now().Format("15:04:05.000"), level, l.facet, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) debugEnabled() bool {
+	return traced["all"] || traced["*"] || traced[l.facet]
+}
+
+// Debug logs a message only when the logger's facet is enabled via
+// STOPWATCH_TRACE.
+func (l *Logger) Debug(format string, args ...interface{}) {
+	if l.debugEnabled() {
+		l.output("DBG", format, args...)
+	}
+}
+
+// Info logs an informational message.
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.output("INF", format, args...)
+}
+
+// Warn logs a recoverable problem.
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.output("WRN", format, args...)
+}
+
+// Fatal logs an unrecoverable error and exits the process with status 1.
+func (l *Logger) Fatal(format string, args ...interface{}) {
+	l.output("FTL", format, args...)
+	os.Exit(1)
+}
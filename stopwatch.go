@@ -0,0 +1,63 @@
+// Package stopwatch implements the core stopwatch model shared by the CLI
+// and any future daemon or server frontends: entries, the storage
+// interface they are persisted through, and a Clock abstraction so callers
+// can control time in tests.
+package stopwatch
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Store.Stop when no entry exists for the given id.
+var ErrNotFound = errors.New("stopwatch: no such id")
+
+// Entry represents a single stopwatch, running or already recorded. Tags
+// are free-form "key=val" strings attached at start time. Laps records the
+// split recorded by each prior call to Store.Lap.
+type Entry struct {
+	ID        string
+	StartTime time.Time
+	Tags      []string
+	Laps      []time.Duration
+}
+
+// Clock abstracts time.Now so callers can substitute a fake clock in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock backed by the real wall clock.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Store persists stopwatch entries. Implementations must be safe for use by
+// a single process at a time; concurrent access across processes is
+// synchronized by the implementation (e.g. jsonstore uses flock).
+type Store interface {
+	// Start records a new stopwatch starting at t, tagged with the given
+	// tags. If id is already running, it is overwritten: the previous
+	// StartTime, tags and laps are discarded.
+	Start(id string, t time.Time, tags ...string) error
+
+	// Stop removes the stopwatch with the given id and returns its entry
+	// (as it was just before removal) along with how long it had been
+	// running. It returns ErrNotFound if no such id exists.
+	Stop(id string) (Entry, time.Duration, error)
+
+	// Lap appends a new split to the stopwatch with the given id --
+	// time elapsed since StartTime minus the sum of prior laps -- and
+	// returns that split without stopping the timer. It returns
+	// ErrNotFound if no such id exists.
+	Lap(id string) (time.Duration, error)
+
+	// List returns every currently running stopwatch.
+	List() ([]Entry, error)
+
+	// Purge discards all stored state.
+	Purge() error
+}